@@ -0,0 +1,148 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantDeletionPolicy controls what happens to the remote 3scale tenant
+// account when its Tenant CR is deleted.
+type TenantDeletionPolicy string
+
+const (
+	// TenantDeletionPolicyDelete deletes the remote 3scale tenant account
+	// when the Tenant CR is deleted. This is the default.
+	TenantDeletionPolicyDelete TenantDeletionPolicy = "Delete"
+	// TenantDeletionPolicyRetain keeps the remote 3scale tenant account
+	// around when the Tenant CR is deleted.
+	TenantDeletionPolicyRetain TenantDeletionPolicy = "Retain"
+)
+
+// TenantProvisioningMode selects how a Tenant's admin user is onboarded and
+// authenticated.
+type TenantProvisioningMode string
+
+const (
+	// TenantProvisioningModeInternal provisions the tenant and its admin
+	// user entirely within 3scale, including the admin password. This is
+	// the default.
+	TenantProvisioningModeInternal TenantProvisioningMode = "Internal"
+	// TenantProvisioningModeExternalIdentity federates the admin user
+	// against spec.identityProviderRef instead of managing a 3scale
+	// password.
+	TenantProvisioningModeExternalIdentity TenantProvisioningMode = "ExternalIdentity"
+)
+
+// IdentityProviderRef references the external identity provider a Tenant's
+// admin user is federated against when provisioningMode is ExternalIdentity.
+type IdentityProviderRef struct {
+	// Name is the OIDC client ID registered with the identity provider.
+	Name string `json:"name"`
+	// Kind identifies the identity provider implementation, e.g. "Keycloak".
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// IssuerURL is the OIDC issuer base URL the token endpoint is resolved against.
+	IssuerURL string `json:"issuerURL"`
+}
+
+// SecretRef references a Secret by name and namespace.
+type SecretRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// AdminPasswordRef references the Secret holding the tenant admin's desired
+// password. Generation is a counter operators can bump to force a password
+// rotation even when the secret's own content is otherwise reused.
+type AdminPasswordRef struct {
+	Name       string `json:"name"`
+	Generation int64  `json:"generation,omitempty"`
+}
+
+// TenantSpec defines the desired state of Tenant
+type TenantSpec struct {
+	OrganizationName string    `json:"organizationName"`
+	Username         string    `json:"username"`
+	Email            string    `json:"email"`
+	TenantSecretRef  SecretRef `json:"tenantSecretRef"`
+
+	// PasswordCredentialsRef references the Secret holding the admin user's
+	// password. Required unless ProvisioningMode is ExternalIdentity, in
+	// which case the admin password is never read and this is ignored.
+	// +optional
+	PasswordCredentialsRef v1.LocalObjectReference `json:"passwordCredentialsRef,omitempty"`
+
+	// DeletionPolicy controls whether the remote 3scale tenant account is
+	// deleted together with the Tenant CR. Defaults to Delete.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy TenantDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// TokenScopes lists the porta access token scopes issued for the
+	// provider-key secret. Defaults to ["account_management"].
+	// +optional
+	TokenScopes []string `json:"tokenScopes,omitempty"`
+
+	// TokenTTL is how long an issued access token remains valid before the
+	// operator rotates it. Defaults to 30 days.
+	// +optional
+	TokenTTL *metav1.Duration `json:"tokenTTL,omitempty"`
+
+	// AdminFirstName reconciles the admin user's first name.
+	// +optional
+	AdminFirstName string `json:"adminFirstName,omitempty"`
+
+	// AdminLastName reconciles the admin user's last name.
+	// +optional
+	AdminLastName string `json:"adminLastName,omitempty"`
+
+	// AdminPasswordRef, if set, reconciles the admin user's password from
+	// the referenced Secret whenever it (or Generation) changes.
+	// +optional
+	AdminPasswordRef *AdminPasswordRef `json:"adminPasswordRef,omitempty"`
+
+	// ProvisioningMode selects how the tenant's admin user is onboarded and
+	// authenticated. Defaults to Internal.
+	// +optional
+	// +kubebuilder:validation:Enum=Internal;ExternalIdentity
+	ProvisioningMode TenantProvisioningMode `json:"provisioningMode,omitempty"`
+
+	// IdentityProviderRef is required when ProvisioningMode is
+	// ExternalIdentity, and references the external identity provider the
+	// admin user is federated against.
+	// +optional
+	IdentityProviderRef *IdentityProviderRef `json:"identityProviderRef,omitempty"`
+}
+
+// TenantStatus defines the observed state of Tenant
+type TenantStatus struct {
+	TenantId int64 `json:"tenantId,omitempty"`
+	AdminId  int64 `json:"adminId,omitempty"`
+
+	// AdminPasswordResourceVersion stashes the generation/resourceVersion of
+	// AdminPasswordRef's Secret last pushed to porta, so a password rotation
+	// is only ever triggered once per secret update.
+	// +optional
+	AdminPasswordResourceVersion string `json:"adminPasswordResourceVersion,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Tenant is the Schema for the tenants API
+type Tenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TenantSpec   `json:"spec,omitempty"`
+	Status TenantStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TenantList contains a list of Tenant
+type TenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Tenant `json:"items"`
+}
@@ -0,0 +1,197 @@
+package tenant
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// defaultTokenTTL is used when spec.tokenTTL is not set on the Tenant CR.
+	defaultTokenTTL = 30 * 24 * time.Hour
+
+	// rotationThreshold is how long before expiry a token is rotated, so a
+	// reconcile loop running less often than that never serves a stale token.
+	rotationThreshold = 24 * time.Hour
+
+	// TenantAccessTokenIDAnnotation records the ID of the access token currently
+	// stored in the secret, so it can be revoked once it's rotated out.
+	TenantAccessTokenIDAnnotation = "tenant.3scale.net/access-token-id"
+	// TenantAccessTokenIssuedAtAnnotation records when the current access token was issued.
+	TenantAccessTokenIssuedAtAnnotation = "tenant.3scale.net/access-token-issued-at"
+	// TenantAccessTokenExpiresAtAnnotation records when the current access token expires.
+	TenantAccessTokenExpiresAtAnnotation = "tenant.3scale.net/access-token-expires-at"
+)
+
+// issueAccessToken provisions a brand new 3scale access token for the
+// tenant's admin account, scoped and time-bound according to
+// spec.tokenScopes/spec.tokenTTL.
+func (r *InternalReconciler) issueAccessToken(tenantDef *porta_client_pkg.Tenant) (*porta_client_pkg.AccessToken, error) {
+	ttl := r.tokenTTL()
+	params := porta_client_pkg.Params{
+		"name":       r.tenantR.Name,
+		"scopes":     r.tokenScopes(),
+		"permission": "rw",
+		"ttl":        int64(ttl.Seconds()),
+	}
+
+	var accessToken *porta_client_pkg.AccessToken
+	err := retryPortaCall(func() error {
+		var err error
+		accessToken, err = r.portaClient.CreateAccessToken(tenantDef.Signup.Account.ID, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accessToken, nil
+}
+
+func (r *InternalReconciler) tokenScopes() []string {
+	if len(r.tenantR.Spec.TokenScopes) == 0 {
+		return []string{"account_management"}
+	}
+	return r.tenantR.Spec.TokenScopes
+}
+
+func (r *InternalReconciler) tokenTTL() time.Duration {
+	if r.tenantR.Spec.TokenTTL == nil {
+		return defaultTokenTTL
+	}
+	return r.tenantR.Spec.TokenTTL.Duration
+}
+
+// rotateTenantProviderKeySecretIfNeeded checks the expiry annotation on the
+// existing secret and, if the token is about to expire (or the annotation is
+// missing/unparseable), issues a new one, updates the secret in place and
+// revokes the previous token.
+func (r *InternalReconciler) rotateTenantProviderKeySecretIfNeeded(tenantDef *porta_client_pkg.Tenant, secret *v1.Secret) error {
+	nn := types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}
+
+	if !r.accessTokenNeedsRotation(secret) {
+		r.logger.Info("Admin user access token secret already exists", "Secret NS", nn.Namespace, "Secret name", nn.Name)
+		return nil
+	}
+
+	r.logger.Info("Rotating admin access token secret", "Secret NS", nn.Namespace, "Secret name", nn.Name)
+
+	previousTokenID := secret.Annotations[TenantAccessTokenIDAnnotation]
+
+	accessToken, err := r.issueAccessToken(tenantDef)
+	if err != nil {
+		return err
+	}
+
+	adminURL, err := URLFromDomain(tenantDef.Signup.Account.AdminDomain)
+	if err != nil {
+		return err
+	}
+
+	updated := newTenantProviderKeySecret(nn, accessToken, r.tokenTTL(), adminURL.String())
+	secret.StringData = updated.StringData
+	secret.Annotations = mergeAccessTokenAnnotations(secret.Annotations, updated.Annotations)
+	if err := r.k8sClient.Update(context.TODO(), secret); err != nil {
+		return err
+	}
+
+	return r.revokeAccessTokenByID(tenantDef, previousTokenID)
+}
+
+// mergeAccessTokenAnnotations overlays the TenantAccessToken* annotations
+// onto a copy of existing, leaving any annotation owned by something else
+// (a user, another controller) untouched.
+func mergeAccessTokenAnnotations(existing, accessTokenAnnotations map[string]string) map[string]string {
+	merged := make(map[string]string, len(existing)+len(accessTokenAnnotations))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range accessTokenAnnotations {
+		merged[k] = v
+	}
+	return merged
+}
+
+func (r *InternalReconciler) accessTokenNeedsRotation(secret *v1.Secret) bool {
+	expiresAtRaw, ok := secret.Annotations[TenantAccessTokenExpiresAtAnnotation]
+	if !ok {
+		return true
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, expiresAtRaw)
+	if err != nil {
+		return true
+	}
+
+	return time.Now().Add(rotationThreshold).After(expiresAt)
+}
+
+func (r *InternalReconciler) revokeAccessTokenByID(tenantDef *porta_client_pkg.Tenant, tokenID string) error {
+	if tokenID == "" {
+		return nil
+	}
+
+	id, err := strconv.ParseInt(tokenID, 10, 64)
+	if err != nil {
+		r.logger.Info("Skipping revocation of previous access token, invalid stored ID", "ID", tokenID)
+		return nil
+	}
+
+	r.logger.Info("Revoking previous access token", "TenantId", tenantDef.Signup.Account.ID, "TokenID", id)
+	err = r.portaClient.DeleteAccessToken(tenantDef.Signup.Account.ID, id)
+	if err != nil && porta_client_pkg.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func newTenantProviderKeySecret(nn types.NamespacedName, accessToken *porta_client_pkg.AccessToken, ttl time.Duration, adminURL string) *v1.Secret {
+	issuedAt := time.Now()
+
+	return &v1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nn.Namespace,
+			Name:      nn.Name,
+			Labels:    map[string]string{"app": "3scale-operator"},
+			Annotations: map[string]string{
+				TenantAccessTokenIDAnnotation:        strconv.FormatInt(accessToken.ID, 10),
+				TenantAccessTokenIssuedAtAnnotation:  issuedAt.Format(time.RFC3339),
+				TenantAccessTokenExpiresAtAnnotation: issuedAt.Add(ttl).Format(time.RFC3339),
+			},
+		},
+		StringData: map[string]string{
+			TenantProviderKeySecretField:    accessToken.Value,
+			TenantAdminDomainKeySecretField: adminURL,
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+}
+
+// federatedProviderKeySecret builds the provider-key secret for tenants
+// provisioned against an external identity provider: no access-token
+// rotation annotations are set, since there's no porta-issued token to
+// expire.
+func federatedProviderKeySecret(nn types.NamespacedName, data map[string]string) *v1.Secret {
+	return &v1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nn.Namespace,
+			Name:      nn.Name,
+			Labels:    map[string]string{"app": "3scale-operator"},
+		},
+		StringData: data,
+		Type:       v1.SecretTypeOpaque,
+	}
+}
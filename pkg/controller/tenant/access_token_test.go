@@ -0,0 +1,90 @@
+package tenant
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func secretWithExpiry(t *testing.T, in time.Duration) *v1.Secret {
+	t.Helper()
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				TenantAccessTokenExpiresAtAnnotation: time.Now().Add(in).Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+func TestAccessTokenNeedsRotation(t *testing.T) {
+	r := &InternalReconciler{}
+
+	t.Run("missing annotation needs rotation", func(t *testing.T) {
+		secret := &v1.Secret{}
+		if !r.accessTokenNeedsRotation(secret) {
+			t.Fatal("expected rotation when expiry annotation is missing")
+		}
+	})
+
+	t.Run("unparseable annotation needs rotation", func(t *testing.T) {
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			TenantAccessTokenExpiresAtAnnotation: "not-a-timestamp",
+		}}}
+		if !r.accessTokenNeedsRotation(secret) {
+			t.Fatal("expected rotation when expiry annotation can't be parsed")
+		}
+	})
+
+	t.Run("well within validity does not need rotation", func(t *testing.T) {
+		secret := secretWithExpiry(t, rotationThreshold*10)
+		if r.accessTokenNeedsRotation(secret) {
+			t.Fatal("did not expect rotation for a token far from expiry")
+		}
+	})
+
+	t.Run("inside rotation threshold needs rotation", func(t *testing.T) {
+		secret := secretWithExpiry(t, rotationThreshold/2)
+		if !r.accessTokenNeedsRotation(secret) {
+			t.Fatal("expected rotation for a token inside the rotation threshold")
+		}
+	})
+
+	t.Run("already expired needs rotation", func(t *testing.T) {
+		secret := secretWithExpiry(t, -time.Hour)
+		if !r.accessTokenNeedsRotation(secret) {
+			t.Fatal("expected rotation for an already-expired token")
+		}
+	})
+}
+
+func TestMergeAccessTokenAnnotations(t *testing.T) {
+	existing := map[string]string{
+		"user-owned-annotation":       "keep-me",
+		TenantAccessTokenIDAnnotation: "old-id",
+	}
+	accessTokenAnnotations := map[string]string{
+		TenantAccessTokenIDAnnotation:        "new-id",
+		TenantAccessTokenIssuedAtAnnotation:  "2026-01-01T00:00:00Z",
+		TenantAccessTokenExpiresAtAnnotation: "2026-02-01T00:00:00Z",
+	}
+
+	merged := mergeAccessTokenAnnotations(existing, accessTokenAnnotations)
+
+	if merged["user-owned-annotation"] != "keep-me" {
+		t.Errorf("mergeAccessTokenAnnotations dropped a foreign annotation, got %v", merged)
+	}
+	if merged[TenantAccessTokenIDAnnotation] != "new-id" {
+		t.Errorf("mergeAccessTokenAnnotations did not overwrite the rotated token ID, got %v", merged)
+	}
+	if merged[TenantAccessTokenIssuedAtAnnotation] != "2026-01-01T00:00:00Z" {
+		t.Errorf("mergeAccessTokenAnnotations did not set the issued-at annotation, got %v", merged)
+	}
+
+	// The original maps must be left untouched.
+	if existing[TenantAccessTokenIDAnnotation] != "old-id" {
+		t.Errorf("mergeAccessTokenAnnotations mutated the existing map in place, got %v", existing)
+	}
+}
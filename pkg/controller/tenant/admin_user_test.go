@@ -0,0 +1,61 @@
+package tenant
+
+import (
+	"testing"
+
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
+)
+
+func TestAdminUserNeedsSync(t *testing.T) {
+	spec := apiv1alpha1.TenantSpec{
+		Username:       "admin",
+		Email:          "admin@example.com",
+		AdminFirstName: "Ada",
+		AdminLastName:  "Lovelace",
+	}
+
+	matching := &porta_client_pkg.User{
+		UserName:  "admin",
+		Email:     "admin@example.com",
+		FirstName: "Ada",
+		LastName:  "Lovelace",
+	}
+	if adminUserNeedsSync(spec, matching) {
+		t.Fatal("adminUserNeedsSync() = true when every field already matches spec")
+	}
+
+	cases := []struct {
+		name string
+		user *porta_client_pkg.User
+	}{
+		{"username drift", &porta_client_pkg.User{UserName: "someone-else", Email: spec.Email, FirstName: spec.AdminFirstName, LastName: spec.AdminLastName}},
+		{"email drift", &porta_client_pkg.User{UserName: spec.Username, Email: "other@example.com", FirstName: spec.AdminFirstName, LastName: spec.AdminLastName}},
+		{"first name drift", &porta_client_pkg.User{UserName: spec.Username, Email: spec.Email, FirstName: "Grace", LastName: spec.AdminLastName}},
+		{"last name drift", &porta_client_pkg.User{UserName: spec.Username, Email: spec.Email, FirstName: spec.AdminFirstName, LastName: "Hopper"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if !adminUserNeedsSync(spec, c.user) {
+				t.Fatalf("adminUserNeedsSync() = false, want true for %s", c.name)
+			}
+		})
+	}
+}
+
+func TestAdminPasswordRefVersion(t *testing.T) {
+	a := adminPasswordRefVersion(1, "1000")
+	b := adminPasswordRefVersion(1, "1000")
+	if a != b {
+		t.Fatalf("adminPasswordRefVersion should be deterministic, got %q and %q", a, b)
+	}
+
+	if adminPasswordRefVersion(1, "1000") == adminPasswordRefVersion(2, "1000") {
+		t.Fatal("adminPasswordRefVersion should change when Generation is bumped")
+	}
+
+	if adminPasswordRefVersion(1, "1000") == adminPasswordRefVersion(1, "1001") {
+		t.Fatal("adminPasswordRefVersion should change when the secret's ResourceVersion changes")
+	}
+}
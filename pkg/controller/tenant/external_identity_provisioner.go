@@ -0,0 +1,193 @@
+package tenant
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// IdentityFederator exchanges a Tenant's spec.identityProviderRef for a
+// token that authenticates as the tenant's federated admin user.
+type IdentityFederator interface {
+	Federate(idpRef *apiv1alpha1.IdentityProviderRef) (string, error)
+}
+
+// oidcClientCredentialsFederator federates against any OIDC-compliant
+// identity provider (Keycloak, Auth0, plain OIDC) using the client
+// credentials grant against idpRef.IssuerURL.
+type oidcClientCredentialsFederator struct {
+	httpClient *http.Client
+}
+
+func (f *oidcClientCredentialsFederator) Federate(idpRef *apiv1alpha1.IdentityProviderRef) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", idpRef.Name)
+
+	resp, err := f.httpClient.PostForm(idpRef.IssuerURL+"/protocol/openid-connect/token", form)
+	if err != nil {
+		return "", fmt.Errorf("federating admin user with identity provider %q: %w", idpRef.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("federating admin user with identity provider %q: unexpected status %d", idpRef.Name, resp.StatusCode)
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("decoding token response from identity provider %q: %w", idpRef.Name, err)
+	}
+
+	return tokenResponse.AccessToken, nil
+}
+
+// externalIdentityProvisioner onboards a tenant whose admin user is
+// federated against an external identity provider (Keycloak, Auth0, plain
+// OIDC) referenced by spec.identityProviderRef, instead of a 3scale-managed
+// password: tenant creation uses a throwaway bootstrap password (3scale
+// signup requires one, but it's never stored or synced from spec), the
+// admin user is never synced against spec.AdminPasswordRef, and the
+// provider-key secret stores the token obtained from the IdP rather than a
+// porta-issued access token.
+type externalIdentityProvisioner struct {
+	*InternalReconciler
+	federator IdentityFederator
+}
+
+func (p *externalIdentityProvisioner) EnsureTenant() (*porta_client_pkg.Tenant, error) {
+	tenantDef, err := p.fetchTenant()
+	if err != nil {
+		return nil, err
+	}
+
+	if tenantDef == nil {
+		return p.createFederatedTenant()
+	}
+
+	if err := p.syncTenant(tenantDef); err != nil {
+		return nil, err
+	}
+
+	return tenantDef, nil
+}
+
+// createFederatedTenant signs up the tenant account with a bootstrap
+// password that is generated on the fly and discarded immediately: the
+// 3scale signup API requires a password, but the federated admin user will
+// never authenticate with it, only with the external IdP.
+func (p *externalIdentityProvisioner) createFederatedTenant() (*porta_client_pkg.Tenant, error) {
+	bootstrapPassword, err := generateBootstrapPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	p.logger.Info("Creating a new tenant via external identity provisioning", "OrganizationName", p.tenantR.Spec.OrganizationName,
+		"Username", p.tenantR.Spec.Username, "Email", p.tenantR.Spec.Email)
+	return p.portaClient.CreateTenant(
+		p.tenantR.Spec.OrganizationName,
+		p.tenantR.Spec.Username,
+		p.tenantR.Spec.Email,
+		bootstrapPassword,
+	)
+}
+
+func generateBootstrapPassword() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating bootstrap password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (p *externalIdentityProvisioner) EnsureAdminUser(tenantDef *porta_client_pkg.Tenant) (*porta_client_pkg.User, error) {
+	idpRef := p.tenantR.Spec.IdentityProviderRef
+	if idpRef == nil {
+		return nil, fmt.Errorf("spec.identityProviderRef is required when spec.provisioningMode is %s",
+			apiv1alpha1.TenantProvisioningModeExternalIdentity)
+	}
+
+	return p.federateAdminUser(tenantDef, idpRef)
+}
+
+// federateAdminUser makes sure the tenant's admin user exists, is active and
+// matches spec via the same syncAdminIdentity path the internal provisioner
+// uses, minus password reconciliation: the account's identity is owned by
+// the external IdP, porta is only ever asked to activate it and keep
+// username/email/name in sync.
+func (p *externalIdentityProvisioner) federateAdminUser(tenantDef *porta_client_pkg.Tenant, idpRef *apiv1alpha1.IdentityProviderRef) (*porta_client_pkg.User, error) {
+	adminUser, err := p.fetchAdminUser(tenantDef)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.syncAdminIdentity(tenantDef, adminUser); err != nil {
+		return nil, err
+	}
+
+	p.logger.Info("Admin user federated with external identity provider",
+		"TenantId", tenantDef.Signup.Account.ID, "UserID", adminUser.ID, "IdentityProvider", idpRef.Name)
+
+	return adminUser, nil
+}
+
+// EnsureAccessTokenSecret stores the token obtained from the external
+// identity provider in the provider-key secret, instead of issuing a porta
+// access token: consumers watching the secret authenticate against the IdP
+// the same way the federated admin user does.
+func (p *externalIdentityProvisioner) EnsureAccessTokenSecret(tenantDef *porta_client_pkg.Tenant) error {
+	idpRef := p.tenantR.Spec.IdentityProviderRef
+	if idpRef == nil {
+		return fmt.Errorf("spec.identityProviderRef is required when spec.provisioningMode is %s",
+			apiv1alpha1.TenantProvisioningModeExternalIdentity)
+	}
+
+	federatedToken, err := p.federator.Federate(idpRef)
+	if err != nil {
+		return err
+	}
+
+	adminURL, err := URLFromDomain(tenantDef.Signup.Account.AdminDomain)
+	if err != nil {
+		return err
+	}
+
+	nn := types.NamespacedName{
+		Name:      p.tenantR.Spec.TenantSecretRef.Name,
+		Namespace: p.tenantR.Spec.TenantSecretRef.Namespace,
+	}
+	secretData := map[string]string{
+		TenantProviderKeySecretField:    federatedToken,
+		TenantAdminDomainKeySecretField: adminURL.String(),
+	}
+
+	existing, err := p.findAccessTokenSecret(nn)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		p.logger.Info("Creating admin access token secret from federated identity", "Secret NS", nn.Namespace, "Secret name", nn.Name)
+		secret := federatedProviderKeySecret(nn, secretData)
+		addOwnerRefToObject(secret, asOwner(p.tenantR))
+		return p.k8sClient.Create(context.TODO(), secret)
+	}
+
+	p.logger.Info("Refreshing admin access token secret from federated identity", "Secret NS", nn.Namespace, "Secret name", nn.Name)
+	existing.StringData = secretData
+	return p.k8sClient.Update(context.TODO(), existing)
+}
+
+func (p *externalIdentityProvisioner) Teardown() error {
+	return p.reconcileDelete()
+}
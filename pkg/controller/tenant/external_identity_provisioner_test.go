@@ -0,0 +1,103 @@
+package tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestOIDCClientCredentialsFederatorFederate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				t.Fatalf("ParseForm() error = %v", err)
+			}
+			if got := r.FormValue("grant_type"); got != "client_credentials" {
+				t.Errorf("grant_type = %q, want client_credentials", got)
+			}
+			if got := r.FormValue("client_id"); got != "my-client" {
+				t.Errorf("client_id = %q, want my-client", got)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"federated-token"}`))
+		}))
+		defer server.Close()
+
+		f := &oidcClientCredentialsFederator{httpClient: server.Client()}
+		token, err := f.Federate(&apiv1alpha1.IdentityProviderRef{Name: "my-client", IssuerURL: server.URL})
+		if err != nil {
+			t.Fatalf("Federate() error = %v", err)
+		}
+		if token != "federated-token" {
+			t.Errorf("Federate() = %q, want federated-token", token)
+		}
+	})
+
+	t.Run("non-200 response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		f := &oidcClientCredentialsFederator{httpClient: server.Client()}
+		_, err := f.Federate(&apiv1alpha1.IdentityProviderRef{Name: "my-client", IssuerURL: server.URL})
+		if err == nil {
+			t.Fatal("Federate() error = nil, want non-nil for a 401 response")
+		}
+	})
+
+	t.Run("unparseable response body", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("not json"))
+		}))
+		defer server.Close()
+
+		f := &oidcClientCredentialsFederator{httpClient: server.Client()}
+		_, err := f.Federate(&apiv1alpha1.IdentityProviderRef{Name: "my-client", IssuerURL: server.URL})
+		if err == nil {
+			t.Fatal("Federate() error = nil, want non-nil for an unparseable body")
+		}
+	})
+}
+
+func TestGenerateBootstrapPassword(t *testing.T) {
+	a, err := generateBootstrapPassword()
+	if err != nil {
+		t.Fatalf("generateBootstrapPassword() error = %v", err)
+	}
+	if len(a) == 0 {
+		t.Fatal("generateBootstrapPassword() returned an empty string")
+	}
+
+	b, err := generateBootstrapPassword()
+	if err != nil {
+		t.Fatalf("generateBootstrapPassword() error = %v", err)
+	}
+	if a == b {
+		t.Fatal("generateBootstrapPassword() returned the same value twice")
+	}
+}
+
+func TestFederatedProviderKeySecret(t *testing.T) {
+	nn := types.NamespacedName{Name: "my-tenant-secret", Namespace: "my-ns"}
+	data := map[string]string{
+		TenantProviderKeySecretField:    "federated-token",
+		TenantAdminDomainKeySecretField: "https://my-tenant-admin.3scale.net",
+	}
+
+	secret := federatedProviderKeySecret(nn, data)
+
+	if secret.Name != nn.Name || secret.Namespace != nn.Namespace {
+		t.Fatalf("federatedProviderKeySecret() name/namespace = %s/%s, want %s/%s",
+			secret.Namespace, secret.Name, nn.Namespace, nn.Name)
+	}
+	if secret.StringData[TenantProviderKeySecretField] != "federated-token" {
+		t.Errorf("StringData[%s] = %q, want federated-token", TenantProviderKeySecretField, secret.StringData[TenantProviderKeySecretField])
+	}
+	if _, hasRotationAnnotation := secret.Annotations[TenantAccessTokenIDAnnotation]; hasRotationAnnotation {
+		t.Error("federatedProviderKeySecret() set a porta access-token rotation annotation, but there's no porta token to rotate")
+	}
+}
@@ -11,11 +11,16 @@ import (
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// tenantFinalizer is set on every Tenant CR so the operator gets a chance to
+// clean up the remote 3scale account and generated secret before the object
+// is removed from the API.
+const tenantFinalizer = "tenant.3scale.net"
+
 // InternalReconciler reconciles a Tenant object
 type InternalReconciler struct {
 	k8sClient   client.Client
@@ -41,17 +46,30 @@ func NewInternalReconciler(k8sClient client.Client, tenantR *apiv1alpha1.Tenant,
 // - Have active admin user
 // - Have secret with tenant's access_token
 func (r *InternalReconciler) Run() error {
-	tenantDef, err := r.reconcileTenant()
+	provisioner := NewTenantProvisioner(r)
+
+	if r.tenantR.DeletionTimestamp != nil {
+		return provisioner.Teardown()
+	}
+
+	if !r.hasFinalizer() {
+		r.addFinalizer()
+		if err := r.k8sClient.Update(context.TODO(), r.tenantR); err != nil {
+			return err
+		}
+	}
+
+	tenantDef, err := provisioner.EnsureTenant()
 	if err != nil {
 		return err
 	}
 
-	adminUserDef, err := r.reconcileAdminUser(tenantDef)
+	adminUserDef, err := provisioner.EnsureAdminUser(tenantDef)
 	if err != nil {
 		return err
 	}
 
-	err = r.reconcileAccessTokenSecret(tenantDef)
+	err = provisioner.EnsureAccessTokenSecret(tenantDef)
 	if err != nil {
 		return err
 	}
@@ -127,7 +145,10 @@ func (r *InternalReconciler) syncTenant(tenantDef *porta_client_pkg.Tenant) erro
 			"support_email": r.tenantR.Spec.Email,
 			"org_name":      r.tenantR.Spec.OrganizationName,
 		}
-		_, err := r.portaClient.UpdateTenant(r.tenantR.Status.TenantId, params)
+		err := retryPortaCall(func() error {
+			_, err := r.portaClient.UpdateTenant(r.tenantR.Status.TenantId, params)
+			return err
+		})
 		if err != nil {
 			return err
 		}
@@ -136,7 +157,7 @@ func (r *InternalReconciler) syncTenant(tenantDef *porta_client_pkg.Tenant) erro
 	return nil
 }
 
-////
+// //
 //
 // This method makes sure admin user:
 // * is active
@@ -167,15 +188,10 @@ func (r *InternalReconciler) reconcileAccessTokenSecret(tenantDef *porta_client_
 	}
 
 	if tenantProviderKeySecret == nil {
-		err = r.createTenantProviderKeySecret(tenantDef, tenantProviderKeySecretNN)
-		if err != nil {
-			return err
-		}
-	} else {
-		r.logger.Info("Admin user access token secret already exists",
-			"Secret NS", tenantProviderKeySecretNN.Namespace, "Secret name", tenantProviderKeySecretNN.Name)
+		return r.createTenantProviderKeySecret(tenantDef, tenantProviderKeySecretNN)
 	}
-	return nil
+
+	return r.rotateTenantProviderKeySecretIfNeeded(tenantDef, tenantProviderKeySecret)
 }
 
 // Create Tenant using porta client
@@ -196,6 +212,11 @@ func (r *InternalReconciler) createTenant() (*porta_client_pkg.Tenant, error) {
 }
 
 func (r *InternalReconciler) getAdminPassword() (string, error) {
+	if r.tenantR.Spec.PasswordCredentialsRef.Name == "" {
+		return "", fmt.Errorf("spec.passwordCredentialsRef is required when spec.provisioningMode is %s",
+			apiv1alpha1.TenantProvisioningModeInternal)
+	}
+
 	// Get tenant admin password from secret reference
 	tenantAdminSecret := &v1.Secret{}
 
@@ -220,7 +241,6 @@ func (r *InternalReconciler) getAdminPassword() (string, error) {
 	return bytes.NewBuffer(passwordByteArray).String(), err
 }
 
-//
 func (r *InternalReconciler) fetchAdminUser(tenantDef *porta_client_pkg.Tenant) (*porta_client_pkg.User, error) {
 	if r.tenantR.Status.AdminId == 0 {
 		// UserID not in status field
@@ -252,7 +272,30 @@ func (r *InternalReconciler) findAdminUser(tenantDef *porta_client_pkg.Tenant) (
 		"TenantId: %d. Admin Username: %s, Admin email: %s", tenantDef.Signup.Account.ID,
 		r.tenantR.Spec.Username, r.tenantR.Spec.Email)
 }
+
+// adminUserNeedsSync reports whether any of the admin user's identity
+// fields has drifted from spec and needs to be pushed to porta.
+func adminUserNeedsSync(spec apiv1alpha1.TenantSpec, adminUser *porta_client_pkg.User) bool {
+	return spec.Username != adminUser.UserName ||
+		spec.Email != adminUser.Email ||
+		spec.AdminFirstName != adminUser.FirstName ||
+		spec.AdminLastName != adminUser.LastName
+}
+
 func (r *InternalReconciler) syncAdminUser(tenantDef *porta_client_pkg.Tenant, adminUser *porta_client_pkg.User) error {
+	if err := r.syncAdminIdentity(tenantDef, adminUser); err != nil {
+		return err
+	}
+
+	return r.syncAdminPassword(tenantDef, adminUser)
+}
+
+// syncAdminIdentity activates the admin user if it's still pending and
+// pushes any username/email/first-name/last-name drift from spec to porta.
+// It deliberately never touches the admin password, so provisioning
+// strategies that don't own a 3scale-managed password (e.g. federated
+// identity) can reuse it without syncAdminUser's password step.
+func (r *InternalReconciler) syncAdminIdentity(tenantDef *porta_client_pkg.Tenant, adminUser *porta_client_pkg.User) error {
 	// If adminUser desired state is not current state, update
 	if adminUser.State == "pending" {
 		err := r.activateAdminUser(tenantDef, adminUser)
@@ -263,27 +306,22 @@ func (r *InternalReconciler) syncAdminUser(tenantDef *porta_client_pkg.Tenant, a
 		r.logger.Info("Admin user already active", "TenantId", tenantDef.Signup.Account.ID, "UserID", adminUser.ID)
 	}
 
-	triggerSync := func() bool {
-		if r.tenantR.Spec.Username != adminUser.UserName {
-			return true
-		}
-
-		if r.tenantR.Spec.Email != adminUser.Email {
-			return true
-		}
-
-		return false
-	}()
-
-	if triggerSync {
+	if adminUserNeedsSync(r.tenantR.Spec, adminUser) {
 		r.logger.Info("Syncing adminUser", "TenantId", tenantDef.Signup.Account.ID, "UserID", adminUser.ID)
 		adminUser.UserName = r.tenantR.Spec.Username
 		adminUser.Email = r.tenantR.Spec.Email
+		adminUser.FirstName = r.tenantR.Spec.AdminFirstName
+		adminUser.LastName = r.tenantR.Spec.AdminLastName
 		params := porta_client_pkg.Params{
-			"username": r.tenantR.Spec.Username,
-			"email":    r.tenantR.Spec.Email,
+			"username":   r.tenantR.Spec.Username,
+			"email":      r.tenantR.Spec.Email,
+			"first_name": r.tenantR.Spec.AdminFirstName,
+			"last_name":  r.tenantR.Spec.AdminLastName,
 		}
-		_, err := r.portaClient.UpdateUser(tenantDef.Signup.Account.ID, adminUser.ID, params)
+		err := retryPortaCall(func() error {
+			_, err := r.portaClient.UpdateUser(tenantDef.Signup.Account.ID, adminUser.ID, params)
+			return err
+		})
 		if err != nil {
 			return err
 		}
@@ -292,9 +330,62 @@ func (r *InternalReconciler) syncAdminUser(tenantDef *porta_client_pkg.Tenant, a
 	return nil
 }
 
+// syncAdminPassword rotates the admin user's password whenever
+// spec.AdminPasswordRef points to a secret that has changed since the last
+// reconcile. Drift is detected by comparing the secret's generation counter
+// and ResourceVersion against the values stashed in TenantStatus, so a
+// password rotation is only ever pushed to porta once per secret update.
+func (r *InternalReconciler) syncAdminPassword(tenantDef *porta_client_pkg.Tenant, adminUser *porta_client_pkg.User) error {
+	if r.tenantR.Spec.AdminPasswordRef == nil {
+		return nil
+	}
+
+	passwordSecret := &v1.Secret{}
+	err := r.k8sClient.Get(context.TODO(),
+		types.NamespacedName{
+			Name:      r.tenantR.Spec.AdminPasswordRef.Name,
+			Namespace: r.tenantR.Namespace,
+		},
+		passwordSecret)
+	if err != nil {
+		return err
+	}
+
+	currentVersion := adminPasswordRefVersion(r.tenantR.Spec.AdminPasswordRef.Generation, passwordSecret.ResourceVersion)
+	if currentVersion == r.tenantR.Status.AdminPasswordResourceVersion {
+		return nil
+	}
+
+	passwordByteArray, ok := passwordSecret.Data[TenantAdminPasswordSecretField]
+	if !ok {
+		return fmt.Errorf("Not found admin password secret (ns: %s, name: %s) attribute: %s",
+			r.tenantR.Namespace, r.tenantR.Spec.AdminPasswordRef.Name, TenantAdminPasswordSecretField)
+	}
+
+	r.logger.Info("Rotating admin user password", "TenantId", tenantDef.Signup.Account.ID, "UserID", adminUser.ID)
+	err = retryPortaCall(func() error {
+		_, err := r.portaClient.UpdateUser(tenantDef.Signup.Account.ID, adminUser.ID, porta_client_pkg.Params{
+			"password": bytes.NewBuffer(passwordByteArray).String(),
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.tenantR.Status.AdminPasswordResourceVersion = currentVersion
+	return nil
+}
+
+func adminPasswordRefVersion(generation int64, resourceVersion string) string {
+	return fmt.Sprintf("%d:%s", generation, resourceVersion)
+}
+
 func (r *InternalReconciler) activateAdminUser(tenantDef *porta_client_pkg.Tenant, adminUser *porta_client_pkg.User) error {
 	r.logger.Info("Activating pending admin user", "Account ID", tenantDef.Signup.Account.ID, "ID", adminUser.ID)
-	return r.portaClient.ActivateUser(tenantDef.Signup.Account.ID, adminUser.ID)
+	return retryPortaCall(func() error {
+		return r.portaClient.ActivateUser(tenantDef.Signup.Account.ID, adminUser.ID)
+	})
 }
 
 func (r *InternalReconciler) findAccessTokenSecret(nn types.NamespacedName) (*v1.Secret, error) {
@@ -316,7 +407,7 @@ func (r *InternalReconciler) findAccessTokenSecret(nn types.NamespacedName) (*v1
 func (r *InternalReconciler) createTenantProviderKeySecret(tenantDef *porta_client_pkg.Tenant, nn types.NamespacedName) error {
 	r.logger.Info("Creating admin access token secret", "Secret NS", nn.Namespace, "Secret name", nn.Name)
 
-	tenantProviderKey, err := r.findTenantProviderKey(tenantDef)
+	accessToken, err := r.issueAccessToken(tenantDef)
 	if err != nil {
 		return err
 	}
@@ -326,44 +417,16 @@ func (r *InternalReconciler) createTenantProviderKeySecret(tenantDef *porta_clie
 		return err
 	}
 
-	secret := &v1.Secret{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "v1",
-			Kind:       "Secret",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: nn.Namespace,
-			Name:      nn.Name,
-			Labels:    map[string]string{"app": "3scale-operator"},
-		},
-		StringData: map[string]string{
-			TenantProviderKeySecretField:    tenantProviderKey,
-			TenantAdminDomainKeySecretField: adminURL.String(),
-		},
-		Type: v1.SecretTypeOpaque,
-	}
+	secret := newTenantProviderKeySecret(nn, accessToken, r.tokenTTL(), adminURL.String())
 	addOwnerRefToObject(secret, asOwner(r.tenantR))
 	return r.k8sClient.Create(context.TODO(), secret)
 }
 
-func (r *InternalReconciler) findTenantProviderKey(tenantDef *porta_client_pkg.Tenant) (string, error) {
-	// Tenant Provider Key is available on provider application list
-	appList, err := r.portaClient.ListApplications(tenantDef.Signup.Account.ID)
-	if err != nil {
-		return "", err
-	}
-
-	if len(appList.Applications) != 1 {
-		return "", fmt.Errorf("Unexpected application list. TenantId: %d", tenantDef.Signup.Account.ID)
-	}
-
-	return appList.Applications[0].Application.UserKey, nil
-}
-
 func (r *InternalReconciler) getTenantStatus(tenantDef *porta_client_pkg.Tenant, adminUserDef *porta_client_pkg.User) *apiv1alpha1.TenantStatus {
 	return &apiv1alpha1.TenantStatus{
-		TenantId: tenantDef.Signup.Account.ID,
-		AdminId:  adminUserDef.ID,
+		TenantId:                     tenantDef.Signup.Account.ID,
+		AdminId:                      adminUserDef.ID,
+		AdminPasswordResourceVersion: r.tenantR.Status.AdminPasswordResourceVersion,
 	}
 }
 
@@ -373,6 +436,164 @@ func (r *InternalReconciler) updateTenantStatus(tenantStatus *apiv1alpha1.Tenant
 		return nil
 	}
 	r.logger.Info("update tenant status", "status", tenantStatus)
-	r.tenantR.Status = *tenantStatus
-	return r.k8sClient.Status().Update(context.TODO(), r.tenantR)
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		tenantR := &apiv1alpha1.Tenant{}
+		if err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: r.tenantR.Name, Namespace: r.tenantR.Namespace}, tenantR); err != nil {
+			return err
+		}
+
+		tenantR.Status = *tenantStatus
+		err := r.k8sClient.Status().Update(context.TODO(), tenantR)
+		if err != nil {
+			return err
+		}
+
+		r.tenantR = tenantR
+		return nil
+	})
+}
+
+// retryPortaCall retries transient errors (connection issues, 409 conflicts
+// and 5xx responses) returned by the 3scale API with the default client-go
+// backoff, so a blip on the porta side does not surface as a reconcile
+// error.
+func retryPortaCall(fn func() error) error {
+	return retry.OnError(retry.DefaultBackoff, isRetriablePortaError, fn)
+}
+
+func isRetriablePortaError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	apiErr, ok := err.(porta_client_pkg.ApiErr)
+	if !ok {
+		// Not a structured porta API error (e.g. a network error), retry it.
+		return true
+	}
+
+	return apiErr.Code() == 409 || apiErr.Code() >= 500
+}
+
+// reconcileDelete runs when the Tenant CR has a non-zero DeletionTimestamp.
+// It tears down the remote 3scale account (unless spec.deletionPolicy is
+// Retain), removes the generated provider-key secret and, finally, clears
+// the finalizer so the API server can garbage collect the CR.
+func (r *InternalReconciler) reconcileDelete() error {
+	if !r.hasFinalizer() {
+		return nil
+	}
+
+	if !shouldDeleteRemoteTenant(r.tenantR.Spec.DeletionPolicy) {
+		r.logger.Info("DeletionPolicy is Retain, skipping remote tenant deletion", "TenantId", r.tenantR.Status.TenantId)
+	} else {
+		tenantDef, err := r.fetchTenant()
+		if err != nil {
+			return err
+		}
+
+		if err := r.revokeAdminAccessToken(tenantDef); err != nil {
+			return err
+		}
+
+		if err := r.deleteRemoteTenant(); err != nil {
+			return err
+		}
+	}
+
+	if err := r.deleteTenantProviderKeySecret(); err != nil {
+		return err
+	}
+
+	r.removeFinalizer()
+	return r.k8sClient.Update(context.TODO(), r.tenantR)
+}
+
+// shouldDeleteRemoteTenant reports whether the remote 3scale tenant account
+// should be torn down for a given spec.DeletionPolicy. Everything but an
+// explicit Retain deletes it.
+func shouldDeleteRemoteTenant(policy apiv1alpha1.TenantDeletionPolicy) bool {
+	return policy != apiv1alpha1.TenantDeletionPolicyRetain
+}
+
+func (r *InternalReconciler) revokeAdminAccessToken(tenantDef *porta_client_pkg.Tenant) error {
+	if tenantDef == nil {
+		return nil
+	}
+
+	nn := types.NamespacedName{
+		Name:      r.tenantR.Spec.TenantSecretRef.Name,
+		Namespace: r.tenantR.Spec.TenantSecretRef.Namespace,
+	}
+
+	secret, err := r.findAccessTokenSecret(nn)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return nil
+	}
+
+	return r.revokeAccessTokenByID(tenantDef, secret.Annotations[TenantAccessTokenIDAnnotation])
+}
+
+func (r *InternalReconciler) deleteRemoteTenant() error {
+	if r.tenantR.Status.TenantId == 0 {
+		// Tenant was never created remotely, nothing to schedule for deletion
+		return nil
+	}
+
+	r.logger.Info("Scheduling tenant account for deletion", "TenantId", r.tenantR.Status.TenantId)
+	err := r.portaClient.DeleteTenant(r.tenantR.Status.TenantId)
+	if err != nil && porta_client_pkg.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *InternalReconciler) deleteTenantProviderKeySecret() error {
+	nn := types.NamespacedName{
+		Name:      r.tenantR.Spec.TenantSecretRef.Name,
+		Namespace: r.tenantR.Spec.TenantSecretRef.Namespace,
+	}
+
+	secret, err := r.findAccessTokenSecret(nn)
+	if err != nil {
+		return err
+	}
+
+	if secret == nil {
+		return nil
+	}
+
+	r.logger.Info("Deleting admin access token secret", "Secret NS", nn.Namespace, "Secret name", nn.Name)
+	err = r.k8sClient.Delete(context.TODO(), secret)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *InternalReconciler) hasFinalizer() bool {
+	for _, f := range r.tenantR.ObjectMeta.Finalizers {
+		if f == tenantFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *InternalReconciler) addFinalizer() {
+	r.tenantR.ObjectMeta.Finalizers = append(r.tenantR.ObjectMeta.Finalizers, tenantFinalizer)
+}
+
+func (r *InternalReconciler) removeFinalizer() {
+	finalizers := r.tenantR.ObjectMeta.Finalizers[:0]
+	for _, f := range r.tenantR.ObjectMeta.Finalizers {
+		if f != tenantFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	r.tenantR.ObjectMeta.Finalizers = finalizers
 }
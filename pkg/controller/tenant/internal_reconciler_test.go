@@ -0,0 +1,56 @@
+package tenant
+
+import (
+	"testing"
+
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestShouldDeleteRemoteTenant(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy apiv1alpha1.TenantDeletionPolicy
+		want   bool
+	}{
+		{"empty policy defaults to delete", "", true},
+		{"explicit Delete", apiv1alpha1.TenantDeletionPolicyDelete, true},
+		{"explicit Retain", apiv1alpha1.TenantDeletionPolicyRetain, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := shouldDeleteRemoteTenant(c.policy); got != c.want {
+				t.Errorf("shouldDeleteRemoteTenant(%q) = %v, want %v", c.policy, got, c.want)
+			}
+		})
+	}
+}
+
+func TestInternalReconcilerFinalizer(t *testing.T) {
+	r := &InternalReconciler{
+		tenantR: &apiv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other.finalizer/foo"}},
+		},
+	}
+
+	if r.hasFinalizer() {
+		t.Fatal("hasFinalizer() = true before it was ever added")
+	}
+
+	r.addFinalizer()
+	if !r.hasFinalizer() {
+		t.Fatal("hasFinalizer() = false after addFinalizer()")
+	}
+	if len(r.tenantR.Finalizers) != 2 {
+		t.Fatalf("addFinalizer() should preserve other finalizers, got %v", r.tenantR.Finalizers)
+	}
+
+	r.removeFinalizer()
+	if r.hasFinalizer() {
+		t.Fatal("hasFinalizer() = true after removeFinalizer()")
+	}
+	if len(r.tenantR.Finalizers) != 1 || r.tenantR.Finalizers[0] != "other.finalizer/foo" {
+		t.Fatalf("removeFinalizer() should only remove tenantFinalizer, got %v", r.tenantR.Finalizers)
+	}
+}
@@ -0,0 +1,59 @@
+package tenant
+
+import (
+	"net/http"
+
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
+)
+
+// TenantProvisioner decouples tenant onboarding from InternalReconciler.Run,
+// letting the reconcile loop dispatch to a concrete provisioning strategy
+// selected through spec.provisioningMode instead of hard-coding a single
+// porta-backed flow.
+type TenantProvisioner interface {
+	// EnsureTenant makes sure the remote 3scale tenant account exists and matches spec.
+	EnsureTenant() (*porta_client_pkg.Tenant, error)
+	// EnsureAdminUser makes sure the tenant's admin user exists, is active and matches spec.
+	EnsureAdminUser(tenantDef *porta_client_pkg.Tenant) (*porta_client_pkg.User, error)
+	// EnsureAccessTokenSecret makes sure the provider-key secret exists and isn't expired.
+	EnsureAccessTokenSecret(tenantDef *porta_client_pkg.Tenant) error
+	// Teardown reverses provisioning when the Tenant CR is being deleted.
+	Teardown() error
+}
+
+// NewTenantProvisioner selects the TenantProvisioner implementation for
+// spec.provisioningMode, defaulting to internal provisioning when unset.
+func NewTenantProvisioner(r *InternalReconciler) TenantProvisioner {
+	switch r.tenantR.Spec.ProvisioningMode {
+	case apiv1alpha1.TenantProvisioningModeExternalIdentity:
+		return &externalIdentityProvisioner{
+			InternalReconciler: r,
+			federator:          &oidcClientCredentialsFederator{httpClient: http.DefaultClient},
+		}
+	default:
+		return &internalProvisioner{InternalReconciler: r}
+	}
+}
+
+// internalProvisioner is the original, fully 3scale-local provisioning
+// strategy: tenant, admin user and password all live in porta.
+type internalProvisioner struct {
+	*InternalReconciler
+}
+
+func (p *internalProvisioner) EnsureTenant() (*porta_client_pkg.Tenant, error) {
+	return p.reconcileTenant()
+}
+
+func (p *internalProvisioner) EnsureAdminUser(tenantDef *porta_client_pkg.Tenant) (*porta_client_pkg.User, error) {
+	return p.reconcileAdminUser(tenantDef)
+}
+
+func (p *internalProvisioner) EnsureAccessTokenSecret(tenantDef *porta_client_pkg.Tenant) error {
+	return p.reconcileAccessTokenSecret(tenantDef)
+}
+
+func (p *internalProvisioner) Teardown() error {
+	return p.reconcileDelete()
+}
@@ -0,0 +1,42 @@
+package tenant
+
+import (
+	"testing"
+
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+)
+
+func TestNewTenantProvisionerSelectsStrategy(t *testing.T) {
+	cases := []struct {
+		name           string
+		mode           apiv1alpha1.TenantProvisioningMode
+		wantInternal   bool
+		wantExternalID bool
+	}{
+		{"empty mode defaults to internal", "", true, false},
+		{"explicit Internal", apiv1alpha1.TenantProvisioningModeInternal, true, false},
+		{"ExternalIdentity", apiv1alpha1.TenantProvisioningModeExternalIdentity, false, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := &InternalReconciler{
+				tenantR: &apiv1alpha1.Tenant{
+					Spec: apiv1alpha1.TenantSpec{ProvisioningMode: c.mode},
+				},
+			}
+
+			provisioner := NewTenantProvisioner(r)
+
+			_, isInternal := provisioner.(*internalProvisioner)
+			if isInternal != c.wantInternal {
+				t.Errorf("NewTenantProvisioner(%q) internalProvisioner = %v, want %v", c.mode, isInternal, c.wantInternal)
+			}
+
+			_, isExternalIdentity := provisioner.(*externalIdentityProvisioner)
+			if isExternalIdentity != c.wantExternalID {
+				t.Errorf("NewTenantProvisioner(%q) externalIdentityProvisioner = %v, want %v", c.mode, isExternalIdentity, c.wantExternalID)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeApiErr satisfies porta_client_pkg.ApiErr for tests without depending
+// on a real porta response.
+type fakeApiErr struct {
+	code int
+}
+
+func (f fakeApiErr) Error() string { return fmt.Sprintf("api error %d", f.code) }
+func (f fakeApiErr) Code() int     { return f.code }
+
+func TestIsRetriablePortaError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error", nil, false},
+		{"network error", errors.New("dial tcp: i/o timeout"), true},
+		{"409 conflict", fakeApiErr{409}, true},
+		{"500 internal server error", fakeApiErr{500}, true},
+		{"503 service unavailable", fakeApiErr{503}, true},
+		{"404 not found", fakeApiErr{404}, false},
+		{"422 unprocessable entity", fakeApiErr{422}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isRetriablePortaError(c.err); got != c.want {
+				t.Errorf("isRetriablePortaError(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRetryPortaCallRetriesUntilSuccess(t *testing.T) {
+	attempts := 0
+	err := retryPortaCall(func() error {
+		attempts++
+		if attempts < 3 {
+			return fakeApiErr{503}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retryPortaCall() = %v, want nil", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("retryPortaCall() made %d attempts, want 3", attempts)
+	}
+}
+
+func TestRetryPortaCallDoesNotRetryNonTransientErrors(t *testing.T) {
+	attempts := 0
+	wantErr := fakeApiErr{422}
+	err := retryPortaCall(func() error {
+		attempts++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("retryPortaCall() = %v, want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Fatalf("retryPortaCall() made %d attempts, want 1 for a non-retriable error", attempts)
+	}
+}